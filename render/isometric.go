@@ -0,0 +1,76 @@
+/*
+Copyright (c) 2022 Andre Renaud <andre@ignavus.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package render
+
+import (
+	"image"
+
+	"github.com/disintegration/imaging"
+	tiled "github.com/lafriks/go-tiled"
+)
+
+// IsometricRendererEngine represents isometric rendering engine.
+type IsometricRendererEngine struct {
+	m *tiled.Map
+}
+
+// Init initializes rendering engine with provided map options.
+func (e *IsometricRendererEngine) Init(m *tiled.Map) {
+	e.m = m
+}
+
+// GetFinalImageSize returns final image size based on tile data and bounding box.
+func (e *IsometricRendererEngine) GetFinalImageSize(bounds Bounds) image.Rectangle {
+	return image.Rect(0, 0,
+		(bounds.limitX+bounds.limitY)*e.m.TileWidth/2,
+		(bounds.limitX+bounds.limitY)*e.m.TileHeight/2)
+}
+
+// RotateTileImage rotates provided tile layer.
+func (e *IsometricRendererEngine) RotateTileImage(tile *tiled.LayerTile, img image.Image) image.Image {
+	timg := img
+	if tile.HorizontalFlip {
+		timg = imaging.FlipH(timg)
+	}
+	if tile.VerticalFlip {
+		timg = imaging.FlipV(timg)
+	}
+	if tile.DiagonalFlip {
+		timg = imaging.FlipH(imaging.Rotate90(timg))
+	}
+
+	return timg
+}
+
+// GetTilePosition returns tile position in image.
+func (e *IsometricRendererEngine) GetTilePosition(x, y int, startOdd bool) image.Rectangle {
+	// Tile (0, Height-1) would otherwise sit (Height-1) half tile widths left
+	// of zero, so shift every tile right by that much to keep positions
+	// non-negative.
+	offset := (e.m.Height - 1) * e.m.TileWidth / 2
+
+	px := (x-y)*e.m.TileWidth/2 + offset
+	py := (x + y) * e.m.TileHeight / 2
+
+	return image.Rect(px, py, px+e.m.TileWidth, py+e.m.TileHeight)
+}
@@ -0,0 +1,129 @@
+// Package mbtiles writes render.TileWriter output into a SQLite MBTiles
+// container. It is split out from render because it pulls in
+// github.com/mattn/go-sqlite3, a cgo dependency; importing this package (and
+// only this package) requires CGO_ENABLED=1 and a C toolchain. Callers who
+// only need render.DirectoryTileWriter stay pure Go.
+package mbtiles
+
+import (
+	"bytes"
+	"database/sql"
+	"image"
+	"image/png"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Writer writes tiles into a SQLite MBTiles container. It implements
+// render.TileWriter and render.RowSetter.
+type Writer struct {
+	db   *sql.DB
+	name string
+
+	rows     int
+	haveZoom bool
+	minZoom  int
+	maxZoom  int
+}
+
+// NewWriter creates or opens the MBTiles container at path and ensures its
+// tiles and metadata tables exist. The container's "name" metadata entry is
+// derived from path's base filename.
+func NewWriter(path string) (*Writer, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS tiles (
+		zoom_level INTEGER,
+		tile_column INTEGER,
+		tile_row INTEGER,
+		tile_data BLOB
+	)`); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if _, err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS tile_index
+		ON tiles (zoom_level, tile_column, tile_row)`); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS metadata (
+		name TEXT,
+		value TEXT
+	)`); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if _, err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS metadata_name
+		ON metadata (name)`); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Writer{db: db, name: name}, nil
+}
+
+// SetRows implements render.RowSetter, recording the total number of tile
+// rows so WriteTile can flip XYZ's top-down y into TMS's bottom-up tile_row.
+func (w *Writer) SetRows(rows int) {
+	w.rows = rows
+}
+
+// WriteTile implements render.TileWriter. Tile rows are stored TMS-style
+// (flipped from XYZ), as the MBTiles spec requires.
+func (w *Writer) WriteTile(z, x, y int, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+
+	tmsRow := w.rows - 1 - y
+	if _, err := w.db.Exec(
+		`INSERT OR REPLACE INTO tiles (zoom_level, tile_column, tile_row, tile_data) VALUES (?, ?, ?, ?)`,
+		z, x, tmsRow, buf.Bytes()); err != nil {
+		return err
+	}
+
+	if !w.haveZoom || z < w.minZoom {
+		w.minZoom = z
+	}
+	if !w.haveZoom || z > w.maxZoom {
+		w.maxZoom = z
+	}
+	w.haveZoom = true
+
+	return nil
+}
+
+// Close writes the container's metadata table and closes the underlying
+// MBTiles database.
+func (w *Writer) Close() error {
+	meta := map[string]string{
+		"name":        w.name,
+		"type":        "baselayer",
+		"version":     "1.1",
+		"format":      "png",
+		"minzoom":     strconv.Itoa(w.minZoom),
+		"maxzoom":     strconv.Itoa(w.maxZoom),
+		"description": w.name,
+	}
+	for k, v := range meta {
+		if _, err := w.db.Exec(
+			`INSERT OR REPLACE INTO metadata (name, value) VALUES (?, ?)`, k, v); err != nil {
+			w.db.Close()
+			return err
+		}
+	}
+
+	return w.db.Close()
+}
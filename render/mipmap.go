@@ -0,0 +1,63 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// RenderMipmapPyramid builds a mipmap pyramid from the current render
+// Result: level 0 is the full-resolution Result, and each subsequent level
+// is a high-quality half-scale Lanczos downsample of the one before it,
+// matching the mipmap selection GPU renderers use. The pyramid is cached on
+// the Renderer for SampleAtScale, and the levels in [minLevel,maxLevel] are
+// returned.
+func (r *Renderer) RenderMipmapPyramid(minLevel, maxLevel int) ([]*image.NRGBA, error) {
+	if minLevel < 0 || maxLevel < minLevel {
+		return nil, fmt.Errorf("tiled/render: invalid mipmap level range [%d,%d]", minLevel, maxLevel)
+	}
+
+	levels := make([]*image.NRGBA, maxLevel+1)
+	levels[0] = r.Result
+	for lvl := 1; lvl <= maxLevel; lvl++ {
+		prev := levels[lvl-1]
+		w := prev.Bounds().Dx() / 2
+		h := prev.Bounds().Dy() / 2
+		if w < 1 {
+			w = 1
+		}
+		if h < 1 {
+			h = 1
+		}
+		levels[lvl] = imaging.Resize(prev, w, h, imaging.Lanczos)
+	}
+
+	r.mipmaps = levels
+	r.mipmapMin = minLevel
+	r.mipmapMax = maxLevel
+
+	return levels[minLevel:], nil
+}
+
+// SampleAtScale returns the coarsest cached mipmap level whose resolution is
+// still at least the given scale (1 = full resolution, 0.5 = half, ...),
+// i.e. level floor(log2(1/scale)), clamped to the range
+// last passed to RenderMipmapPyramid. It must be called after
+// RenderMipmapPyramid.
+func (r *Renderer) SampleAtScale(scale float64) *image.NRGBA {
+	if r.mipmaps == nil {
+		return r.Result
+	}
+
+	level := int(math.Floor(math.Log2(1 / scale)))
+	if level < r.mipmapMin {
+		level = r.mipmapMin
+	}
+	if level > r.mipmapMax {
+		level = r.mipmapMax
+	}
+
+	return r.mipmaps[level]
+}
@@ -0,0 +1,56 @@
+package render
+
+import (
+	"image"
+	"sync"
+)
+
+// tileDecodeGroup deduplicates concurrent tile decode requests for the same
+// GID, similar in spirit to golang.org/x/sync/singleflight: if two workers
+// miss the cache for the same GID at once, only one of them actually decodes
+// the tileset image.
+type tileDecodeGroup struct {
+	mu    sync.Mutex
+	calls map[uint32]*tileDecodeCall
+}
+
+type tileDecodeCall struct {
+	wg  sync.WaitGroup
+	img image.Image
+	err error
+}
+
+// Do executes fn for key, or waits for and returns the result of an
+// in-flight call for the same key.
+func (g *tileDecodeGroup) Do(key uint32, fn func() (image.Image, error)) (image.Image, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[uint32]*tileDecodeCall)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.img, c.err
+	}
+
+	c := &tileDecodeCall{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.img, c.err = fn()
+	c.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return c.img, c.err
+}
+
+// SetParallelism sets the maximum number of tile decode jobs that
+// _renderLayer will run concurrently. n <= 1 renders tiles serially, which
+// is also the default.
+func (r *Renderer) SetParallelism(n int) {
+	r.parallelism = n
+}
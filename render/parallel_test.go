@@ -0,0 +1,137 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"testing/fstest"
+
+	tiled "github.com/lafriks/go-tiled"
+)
+
+// newImageCollectionMap builds an in-memory map covered by a single layer
+// that draws from numTilesets distinct image-collection tilesets (one tile
+// image per tileset, Tileset.Image == nil), cycling through them across a
+// cols x rows grid. That's the worst case for decoding: every tile needs
+// its own file decoded rather than a single tileset image cropped once, so
+// it's the scenario a bounded worker pool helps most.
+func newImageCollectionMap(numTilesets, tileSize, cols, rows int) (*tiled.Map, fstest.MapFS) {
+	fs := make(fstest.MapFS, numTilesets)
+	tilesets := make([]*tiled.Tileset, numTilesets)
+	for i := 0; i < numTilesets; i++ {
+		path := fmt.Sprintf("tileset%d.png", i)
+		fs[path] = &fstest.MapFile{Data: encodeSolidPNG(tileSize, tileSize, uint8(i))}
+
+		tilesets[i] = &tiled.Tileset{
+			FirstGID:  uint32(i) + 1,
+			TileCount: 1,
+			Tiles: []*tiled.TilesetTile{
+				{ID: 0, Image: &tiled.Image{Source: path, Width: tileSize, Height: tileSize}},
+			},
+		}
+	}
+
+	tiles := make([]*tiled.LayerTile, cols*rows)
+	for i := range tiles {
+		ts := tilesets[i%numTilesets]
+		tiles[i] = &tiled.LayerTile{Tileset: ts, ID: 0}
+	}
+
+	m := &tiled.Map{
+		Orientation: "orthogonal",
+		RenderOrder: "right-down",
+		Width:       cols,
+		Height:      rows,
+		TileWidth:   tileSize,
+		TileHeight:  tileSize,
+		Tilesets:    tilesets,
+		Layers: []*tiled.Layer{
+			{ID: 0, Visible: true, Opacity: 1, Tiles: tiles},
+		},
+	}
+
+	return m, fs
+}
+
+// encodeSolidPNG renders a w x h solid-color PNG so each fixture tileset
+// decodes to a distinct, verifiable color.
+func encodeSolidPNG(w, h int, shade uint8) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	c := color.NRGBA{R: shade, G: shade, B: shade, A: 255}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+func renderImageCollectionMap(tb testing.TB, parallelism, numTilesets, tileSize, cols, rows int) *Renderer {
+	tb.Helper()
+
+	m, fs := newImageCollectionMap(numTilesets, tileSize, cols, rows)
+	r, err := NewRendererWithFileSystem(m, fs)
+	if err != nil {
+		tb.Fatalf("NewRendererWithFileSystem: %v", err)
+	}
+	r.SetParallelism(parallelism)
+
+	if err := r.RenderVisibleLayers(); err != nil {
+		tb.Fatalf("RenderVisibleLayers: %v", err)
+	}
+
+	return r
+}
+
+// TestRenderLayerParallelMatchesSerial proves the bounded worker pool added
+// for concurrent tile decoding produces byte-identical output to the serial
+// path, since compositing always happens back on the calling goroutine in
+// job order regardless of decode order.
+func TestRenderLayerParallelMatchesSerial(t *testing.T) {
+	const numTilesets, tileSize, cols, rows = 12, 8, 10, 10
+
+	serial := renderImageCollectionMap(t, 1, numTilesets, tileSize, cols, rows)
+	parallel := renderImageCollectionMap(t, 8, numTilesets, tileSize, cols, rows)
+
+	if !bytes.Equal(serial.Result.Pix, parallel.Result.Pix) {
+		t.Fatal("parallel render output differs from serial render output")
+	}
+}
+
+// BenchmarkRenderLayerSerial and BenchmarkRenderLayerParallel measure the
+// speedup the bounded worker pool gives on a map backed by many
+// image-collection tilesets, where every tile requires its own file decode.
+func BenchmarkRenderLayerSerial(b *testing.B) {
+	benchmarkRenderImageCollectionMap(b, 1)
+}
+
+func BenchmarkRenderLayerParallel(b *testing.B) {
+	benchmarkRenderImageCollectionMap(b, 8)
+}
+
+func benchmarkRenderImageCollectionMap(b *testing.B, parallelism int) {
+	const numTilesets, tileSize, cols, rows = 64, 64, 32, 32
+
+	m, fs := newImageCollectionMap(numTilesets, tileSize, cols, rows)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r, err := NewRendererWithFileSystem(m, fs)
+		if err != nil {
+			b.Fatalf("NewRendererWithFileSystem: %v", err)
+		}
+		r.SetParallelism(parallelism)
+
+		if err := r.RenderVisibleLayers(); err != nil {
+			b.Fatalf("RenderVisibleLayers: %v", err)
+		}
+	}
+}
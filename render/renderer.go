@@ -33,6 +33,7 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"sync"
 
 	"github.com/disintegration/imaging"
 	"github.com/lafriks/go-tiled"
@@ -62,6 +63,15 @@ type Renderer struct {
 	Result       *image.NRGBA // The image result after rendering using the Render functions.
 	ResultBounds Bounds
 	tileCache    map[uint32]image.Image
+	cacheMu      sync.RWMutex
+	decodeGroup  tileDecodeGroup
+	parallelism  int
+	layerBlends  map[int]BlendFunc
+	medianAccum  *pixelAccumulator
+	mipmaps      []*image.NRGBA
+	mipmapMin    int
+	mipmapMax    int
+	thumbnails   map[string]*image.NRGBA
 	engine       RendererEngine
 	fs           fs.FS
 }
@@ -105,6 +115,10 @@ func NewRendererWithFileSystem(m *tiled.Map, fs fs.FS) (*Renderer, error) {
 		r.engine = &OrthogonalRendererEngine{}
 	} else if r.m.Orientation == "hexagonal" {
 		r.engine = &HexagonalRendererEngine{}
+	} else if r.m.Orientation == "isometric" {
+		r.engine = &IsometricRendererEngine{}
+	} else if r.m.Orientation == "staggered" {
+		r.engine = &StaggeredRendererEngine{}
 	} else {
 		return nil, ErrUnsupportedOrientation
 	}
@@ -124,48 +138,169 @@ func (r *Renderer) open(f string) (io.ReadCloser, error) {
 }
 
 func (r *Renderer) getTileImage(tile *tiled.LayerTile) (image.Image, error) {
-	timg, ok := r.tileCache[tile.Tileset.FirstGID+tile.ID]
+	gid := tile.Tileset.FirstGID + tile.ID
+
+	r.cacheMu.RLock()
+	timg, ok := r.tileCache[gid]
+	r.cacheMu.RUnlock()
 	if ok {
 		return r.engine.RotateTileImage(tile, timg), nil
 	}
-	// Precache all tiles in tileset
-	if tile.Tileset.Image == nil {
-		for i := 0; i < len(tile.Tileset.Tiles); i++ {
-			if tile.Tileset.Tiles[i].ID == tile.ID {
-				sf, err := r.open(tile.Tileset.GetFileFullPath(tile.Tileset.Tiles[i].Image.Source))
-				if err != nil {
-					return nil, err
+
+	timg, err := r.decodeGroup.Do(gid, func() (image.Image, error) {
+		r.cacheMu.RLock()
+		cached, ok := r.tileCache[gid]
+		r.cacheMu.RUnlock()
+		if ok {
+			return cached, nil
+		}
+
+		var timg image.Image
+		// Precache all tiles in tileset
+		if tile.Tileset.Image == nil {
+			for i := 0; i < len(tile.Tileset.Tiles); i++ {
+				if tile.Tileset.Tiles[i].ID == tile.ID {
+					sf, err := r.open(tile.Tileset.GetFileFullPath(tile.Tileset.Tiles[i].Image.Source))
+					if err != nil {
+						return nil, err
+					}
+					defer sf.Close()
+					timg, _, err = image.Decode(sf)
+					if err != nil {
+						return nil, err
+					}
+					r.cacheMu.Lock()
+					r.tileCache[gid] = timg
+					r.cacheMu.Unlock()
 				}
-				defer sf.Close()
-				timg, _, err = image.Decode(sf)
-				if err != nil {
-					return nil, err
+			}
+		} else {
+			sf, err := r.open(tile.Tileset.GetFileFullPath(tile.Tileset.Image.Source))
+			if err != nil {
+				return nil, err
+			}
+			defer sf.Close()
+
+			img, _, err := image.Decode(sf)
+			if err != nil {
+				return nil, err
+			}
+
+			r.cacheMu.Lock()
+			for i := uint32(0); i < uint32(tile.Tileset.TileCount); i++ {
+				rect := tile.Tileset.GetTileRect(i)
+				cropped := imaging.Crop(img, rect)
+				r.tileCache[i+tile.Tileset.FirstGID] = cropped
+				if tile.ID == i {
+					timg = cropped
 				}
-				r.tileCache[tile.Tileset.FirstGID+tile.ID] = timg
 			}
+			r.cacheMu.Unlock()
 		}
-	} else {
-		sf, err := r.open(tile.Tileset.GetFileFullPath(tile.Tileset.Image.Source))
-		if err != nil {
-			return nil, err
+
+		return timg, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.engine.RotateTileImage(tile, timg), nil
+}
+
+// _compositeTile draws an already-decoded tile image into r.Result, honoring
+// any BlendFunc configured for layer via SetLayerBlend or a "blendmode"
+// custom property.
+func (r *Renderer) _compositeTile(layer *tiled.Layer, img image.Image, x int, y int, startOdd bool) {
+	pos := r.engine.GetTilePosition(x, y, startOdd)
+
+	fn, median := r.resolveBlendMode(layer)
+	if median {
+		r.accumulateMedianTile(layer, img, pos)
+		return
+	}
+
+	if fn == nil {
+		if layer.Opacity < 1 {
+			mask := image.NewUniform(color.Alpha{uint8(layer.Opacity * 255)})
+
+			draw.DrawMask(r.Result, pos, img, img.Bounds().Min, mask, mask.Bounds().Min, draw.Over)
+		} else {
+			draw.Draw(r.Result, pos, img, img.Bounds().Min, draw.Over)
 		}
-		defer sf.Close()
+		return
+	}
+
+	r.blendTile(layer, img, pos, fn)
+}
+
+// blendTile composites img into Result pixel-by-pixel using fn instead of
+// the default draw.Over.
+func (r *Renderer) blendTile(layer *tiled.Layer, img image.Image, pos image.Rectangle, fn BlendFunc) {
+	srcBounds := img.Bounds()
+	resultBounds := r.Result.Bounds()
+
+	for dy := 0; dy < pos.Dy(); dy++ {
+		for dx := 0; dx < pos.Dx(); dx++ {
+			sp := image.Point{X: srcBounds.Min.X + dx, Y: srcBounds.Min.Y + dy}
+			if !sp.In(srcBounds) {
+				continue
+			}
 
-		img, _, err := image.Decode(sf)
-		if err != nil {
-			return nil, err
+			dp := image.Point{X: pos.Min.X + dx, Y: pos.Min.Y + dy}
+			if !dp.In(resultBounds) {
+				continue
+			}
+
+			sr, sg, sb, sa := img.At(sp.X, sp.Y).RGBA()
+			if sa == 0 {
+				continue
+			}
+			src := color.NRGBA{R: uint8(sr >> 8), G: uint8(sg >> 8), B: uint8(sb >> 8), A: uint8(sa >> 8)}
+			dst := r.Result.NRGBAAt(dp.X, dp.Y)
+
+			blended := fn(dst, src)
+			alpha := float64(src.A) / 255 * float64(layer.Opacity)
+			r.Result.SetNRGBA(dp.X, dp.Y, lerpNRGBA(dst, blended, alpha))
 		}
+	}
+}
+
+// accumulateMedianTile buffers img's pixels for later resolution by Flush,
+// instead of drawing them into Result straight away.
+func (r *Renderer) accumulateMedianTile(layer *tiled.Layer, img image.Image, pos image.Rectangle) {
+	if r.medianAccum == nil {
+		r.medianAccum = newPixelAccumulator()
+	}
 
-		for i := uint32(0); i < uint32(tile.Tileset.TileCount); i++ {
-			rect := tile.Tileset.GetTileRect(i)
-			r.tileCache[i+tile.Tileset.FirstGID] = imaging.Crop(img, rect)
-			if tile.ID == i {
-				timg = r.tileCache[i+tile.Tileset.FirstGID]
+	srcBounds := img.Bounds()
+	for dy := 0; dy < pos.Dy(); dy++ {
+		for dx := 0; dx < pos.Dx(); dx++ {
+			sp := image.Point{X: srcBounds.Min.X + dx, Y: srcBounds.Min.Y + dy}
+			if !sp.In(srcBounds) {
+				continue
 			}
+
+			sr, sg, sb, sa := img.At(sp.X, sp.Y).RGBA()
+			if sa == 0 {
+				continue
+			}
+			if layer.Opacity < 1 {
+				sa = uint32(float64(sa) * float64(layer.Opacity))
+			}
+
+			dp := image.Point{X: pos.Min.X + dx, Y: pos.Min.Y + dy}
+			r.medianAccum.add(dp, color.NRGBA{R: uint8(sr >> 8), G: uint8(sg >> 8), B: uint8(sb >> 8), A: uint8(sa >> 8)})
 		}
 	}
+}
 
-	return r.engine.RotateTileImage(tile, timg), nil
+// Flush resolves any buffered median-blend contributions into Result. Call
+// it once after rendering every layer tagged with the "median" blendmode.
+func (r *Renderer) Flush() {
+	if r.medianAccum == nil {
+		return
+	}
+	r.medianAccum.Flush(r.Result)
 }
 
 func (r *Renderer) _renderTile(layer *tiled.Layer, i int, x int, y int, startOdd bool) error {
@@ -178,47 +313,100 @@ func (r *Renderer) _renderTile(layer *tiled.Layer, i int, x int, y int, startOdd
 		return err
 	}
 
-	pos := r.engine.GetTilePosition(x, y, startOdd)
-
-	if layer.Opacity < 1 {
-		mask := image.NewUniform(color.Alpha{uint8(layer.Opacity * 255)})
-
-		draw.DrawMask(r.Result, pos, img, img.Bounds().Min, mask, mask.Bounds().Min, draw.Over)
-	} else {
-		draw.Draw(r.Result, pos, img, img.Bounds().Min, draw.Over)
-	}
+	r._compositeTile(layer, img, x, y, startOdd)
 
 	return nil
 }
 
+// tileRenderJob is a single tile's position within the render traversal,
+// kept separate from its decode result so decoding can run out of order
+// while compositing stays deterministic.
+type tileRenderJob struct {
+	tileIndex int
+	x, y      int
+}
+
 func (r *Renderer) _renderLayer(layer *tiled.Layer) error {
+	xs := r.ResultBounds.offsetX
+	xe := r.ResultBounds.offsetX + r.ResultBounds.limitX
+	if xe > r.m.Width {
+		xe = r.m.Width
+	}
+	ys := r.ResultBounds.offsetY
+	ye := r.ResultBounds.offsetY + r.ResultBounds.limitY
+	if ye > r.m.Height {
+		ye = r.m.Height
+	}
 
-	var xs, xe, ys, ye int
-	if (r.m.Orientation == "hexagonal" || r.m.Orientation == "orthogonal") && r.m.RenderOrder == "right-down" {
-		xs = r.ResultBounds.offsetX
-		xe = r.ResultBounds.offsetX + r.ResultBounds.limitX
-		if xe > r.m.Width {
-			xe = r.m.Width
-		}
-		ys = r.ResultBounds.offsetY
-		ye = r.ResultBounds.offsetY + r.ResultBounds.limitY
-		if ye > r.m.Height {
-			ye = r.m.Height
-		}
-	} else {
+	reverseX, reverseY := false, false
+	switch r.m.RenderOrder {
+	case "", "right-down":
+	case "left-down":
+		reverseX = true
+	case "right-up":
+		reverseY = true
+	case "left-up":
+		reverseX, reverseY = true, true
+	default:
 		return ErrUnsupportedRenderOrder
 	}
-	cnt := 0
+
 	startOdd := r.ResultBounds.offsetY%2 == 1
-	for y := ys; y < ye; y++ {
-		for x := xs; x < xe; x++ {
-			cnt++
+
+	var jobs []tileRenderJob
+	for yi := 0; yi < ye-ys; yi++ {
+		y := ys + yi
+		if reverseY {
+			y = ye - 1 - yi
+		}
+		for xi := 0; xi < xe-xs; xi++ {
+			x := xs + xi
+			if reverseX {
+				x = xe - 1 - xi
+			}
 			i := y*r.m.Width + x
-			if err := r._renderTile(layer, i, x-xs, y-ys, startOdd); err != nil {
+			if layer.Tiles[i].IsNil() {
+				continue
+			}
+			jobs = append(jobs, tileRenderJob{tileIndex: i, x: x - xs, y: y - ys})
+		}
+	}
+
+	if r.parallelism <= 1 {
+		for _, job := range jobs {
+			if err := r._renderTile(layer, job.tileIndex, job.x, job.y, startOdd); err != nil {
 				return err
 			}
 		}
+		return nil
+	}
+
+	// Decode tiles in a bounded worker pool, keyed by their position in
+	// jobs, then composite them back in that same (deterministic) order
+	// on this goroutine so the output matches the serial path exactly.
+	decoded := make([]image.Image, len(jobs))
+	errs := make([]error, len(jobs))
+
+	sem := make(chan struct{}, r.parallelism)
+	var wg sync.WaitGroup
+	for idx, job := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int, job tileRenderJob) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			decoded[idx], errs[idx] = r.getTileImage(layer.Tiles[job.tileIndex])
+		}(idx, job)
+	}
+	wg.Wait()
+
+	for idx, job := range jobs {
+		if errs[idx] != nil {
+			return errs[idx]
+		}
+		r._compositeTile(layer, decoded[idx], job.x, job.y, startOdd)
 	}
+
 	return nil
 }
 
@@ -243,7 +431,9 @@ func (r *Renderer) RenderLayer(id int) error {
 	return r._renderLayer(r.m.Layers[id])
 }
 
-// RenderVisibleLayers renders all visible map layers.
+// RenderVisibleLayers renders all visible map layers, then calls Flush so
+// any layers tagged with the "median" blendmode custom property are
+// resolved into Result rather than left buffered.
 func (r *Renderer) RenderVisibleLayers() error {
 	for i := range r.m.Layers {
 		if !r.m.Layers[i].Visible {
@@ -255,6 +445,8 @@ func (r *Renderer) RenderVisibleLayers() error {
 		}
 	}
 
+	r.Flush()
+
 	return nil
 }
 
@@ -0,0 +1,59 @@
+/*
+Copyright (c) 2022 Andre Renaud <andre@ignavus.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package render
+
+import (
+	"image"
+
+	tiled "github.com/lafriks/go-tiled"
+)
+
+// StaggeredRendererEngine represents staggered rendering engine. Staggered
+// maps use the same tile math as hexagonal ones with no flat hex side, so
+// this delegates to HexagonalRendererEngine against a map with
+// HexSideLength forced to zero.
+type StaggeredRendererEngine struct {
+	hex HexagonalRendererEngine
+}
+
+// Init initializes rendering engine with provided map options.
+func (e *StaggeredRendererEngine) Init(m *tiled.Map) {
+	hexMap := *m
+	hexMap.HexSideLength = 0
+	e.hex.Init(&hexMap)
+}
+
+// GetFinalImageSize returns final image size based on tile data and bounding box.
+func (e *StaggeredRendererEngine) GetFinalImageSize(bounds Bounds) image.Rectangle {
+	return e.hex.GetFinalImageSize(bounds)
+}
+
+// RotateTileImage rotates provided tile layer.
+func (e *StaggeredRendererEngine) RotateTileImage(tile *tiled.LayerTile, img image.Image) image.Image {
+	return e.hex.RotateTileImage(tile, img)
+}
+
+// GetTilePosition returns tile position in image.
+func (e *StaggeredRendererEngine) GetTilePosition(x, y int, startOdd bool) image.Rectangle {
+	return e.hex.GetTilePosition(x, y, startOdd)
+}
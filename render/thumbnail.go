@@ -0,0 +1,76 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+
+	"github.com/disintegration/imaging"
+)
+
+// Thumbnail scale methods.
+const (
+	// ThumbnailScale resizes the image to fit within the requested dimensions,
+	// preserving aspect ratio. One dimension may end up smaller than requested.
+	ThumbnailScale = "scale"
+	// ThumbnailCrop resizes the image to fill the requested dimensions,
+	// cropping any excess evenly from both sides.
+	ThumbnailCrop = "crop"
+)
+
+// ThumbnailSpec describes a single thumbnail to generate from a rendered map,
+// so that a fixed list of sizes can be declared up front and baked out in one pass.
+type ThumbnailSpec struct {
+	Name   string // Name used as the map key and output file base name.
+	Width  int
+	Height int
+	Method string // ThumbnailScale or ThumbnailCrop.
+}
+
+// RenderThumbnails produces a set of thumbnails from the current render Result,
+// keyed by ThumbnailSpec.Name. It must be called after a full render. The
+// result is cached on the Renderer for SaveThumbnailsAsPng.
+func (r *Renderer) RenderThumbnails(specs []ThumbnailSpec) (map[string]*image.NRGBA, error) {
+	thumbs := make(map[string]*image.NRGBA, len(specs))
+	for _, spec := range specs {
+		var img *image.NRGBA
+		switch spec.Method {
+		case ThumbnailScale:
+			img = imaging.Fit(r.Result, spec.Width, spec.Height, imaging.Lanczos)
+		case ThumbnailCrop:
+			img = imaging.Fill(r.Result, spec.Width, spec.Height, imaging.Center, imaging.Lanczos)
+		default:
+			return nil, fmt.Errorf("tiled/render: unsupported thumbnail method %q", spec.Method)
+		}
+		thumbs[spec.Name] = img
+	}
+
+	r.thumbnails = thumbs
+
+	return thumbs, nil
+}
+
+// SaveThumbnailsAsPng writes the thumbnails from the last RenderThumbnails
+// call to dir/<name>.png. It must be called after RenderThumbnails.
+func (r *Renderer) SaveThumbnailsAsPng(dir string) error {
+	if r.thumbnails == nil {
+		return fmt.Errorf("tiled/render: SaveThumbnailsAsPng called before RenderThumbnails")
+	}
+
+	for name, thumb := range r.thumbnails {
+		f, err := os.Create(filepath.Join(dir, name+".png"))
+		if err != nil {
+			return err
+		}
+		err = func() error {
+			defer f.Close()
+			return imaging.Encode(f, thumb, imaging.PNG)
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// TileWriter receives rendered map tiles as RenderTiled produces them.
+// RenderTiled reuses img's pixel buffer for the next tile, so WriteTile
+// must not keep img after it returns.
+type TileWriter interface {
+	WriteTile(z, x, y int, img image.Image) error
+}
+
+// RowSetter is implemented by TileWriters that need to know the total
+// number of tile rows a RenderTiled run will produce before any tiles
+// arrive, such as mbtiles.Writer flipping Y to TMS's bottom-up row order.
+// RenderTiled calls SetRows once, before writing the first tile.
+type RowSetter interface {
+	SetRows(rows int)
+}
+
+// RenderTiled renders the map in tileSize x tileSize grid-tile chunks and
+// hands each one to w as it's produced. While it runs, Result is a single
+// region-sized buffer reused for every chunk. It does not bound the
+// renderer's peak memory to one chunk: the renderer allocates a full-map
+// Result when it is created, and RenderTiled allocates a fresh full-map
+// Result when it restores the original bounds on return. It always reports
+// z as 0 since it produces one resolution level; pair it with
+// RenderMipmapPyramid for a full deep-zoom pipeline.
+func (r *Renderer) RenderTiled(w TileWriter, tileSize int) error {
+	if tileSize < 1 {
+		return fmt.Errorf("tiled/render: tileSize must be >= 1")
+	}
+
+	origBounds := r.ResultBounds
+	defer func() {
+		r.ResultBounds = origBounds
+		r.Clear()
+	}()
+
+	cols := (r.m.Width + tileSize - 1) / tileSize
+	rows := (r.m.Height + tileSize - 1) / tileSize
+
+	if rs, ok := w.(RowSetter); ok {
+		rs.SetRows(rows)
+	}
+
+	var buf *image.NRGBA
+	for ty := 0; ty < rows; ty++ {
+		for tx := 0; tx < cols; tx++ {
+			r.ResultBounds = Bounds{}
+			r.ResultBounds.SetLimit(tileSize, tileSize)
+			r.ResultBounds.AddOffset(tx*tileSize, ty*tileSize)
+			if buf == nil {
+				r.Clear()
+				buf = r.Result
+			} else {
+				for i := range buf.Pix {
+					buf.Pix[i] = 0
+				}
+				r.Result = buf
+			}
+
+			if err := r.RenderVisibleLayers(); err != nil {
+				return err
+			}
+
+			if err := w.WriteTile(0, tx, ty, r.Result); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// DirectoryTileWriter writes tiles as dir/z/x/y.png, the layout web map
+// viewers expect (Slippy Map / XYZ).
+type DirectoryTileWriter struct {
+	Dir string
+}
+
+// NewDirectoryTileWriter creates a TileWriter that emits PNGs under dir.
+func NewDirectoryTileWriter(dir string) *DirectoryTileWriter {
+	return &DirectoryTileWriter{Dir: dir}
+}
+
+// WriteTile implements TileWriter.
+func (w *DirectoryTileWriter) WriteTile(z, x, y int, img image.Image) error {
+	dir := filepath.Join(w.Dir, strconv.Itoa(z), strconv.Itoa(x))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Join(dir, strconv.Itoa(y)+".png"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return png.Encode(f, img)
+}